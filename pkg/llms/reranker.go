@@ -0,0 +1,179 @@
+package llms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/getzep/zep/pkg/models"
+)
+
+// RerankResult pairs a candidate document's original index with its
+// cross-encoder score, so callers can reorder a slice without losing track
+// of where each score came from.
+type RerankResult struct {
+	Index int
+	Score float64
+}
+
+// Reranker scores (query, document) pairs jointly with a cross-encoder,
+// parallel to the embedding models returned by GetEmbeddingModel. Unlike a
+// bi-encoder's cosine similarity, a cross-encoder attends over the query and
+// document together, which typically lifts nDCG well past what recall-stage
+// similarity search alone can reach.
+type Reranker interface {
+	// Rerank scores each of documents against query and returns results
+	// sorted by Score, descending.
+	Rerank(ctx context.Context, query string, documents []string) ([]RerankResult, error)
+}
+
+// GetReranker returns the Reranker configured via the `rerank.provider` and
+// `rerank.model` config keys. An empty or unrecognised provider returns an
+// error.
+func GetReranker(appState *models.AppState) (Reranker, error) {
+	if appState == nil || appState.Config == nil {
+		return nil, errors.New("nil appState or config")
+	}
+
+	cfg := appState.Config.Rerank
+	switch cfg.Provider {
+	case "cohere":
+		return NewCohereReranker(cfg.Model), nil
+	case "bge", "local":
+		return NewHTTPReranker(cfg.ServiceURL, cfg.Model), nil
+	default:
+		return nil, fmt.Errorf("unsupported rerank provider: %s", cfg.Provider)
+	}
+}
+
+const cohereRerankURL = "https://api.cohere.ai/v1/rerank"
+
+// CohereReranker calls Cohere's hosted Rerank endpoint.
+type CohereReranker struct {
+	model  string
+	apiKey string
+	client *http.Client
+}
+
+// NewCohereReranker returns a Reranker backed by Cohere's Rerank API,
+// authenticated via the COHERE_API_KEY environment variable.
+func NewCohereReranker(model string) *CohereReranker {
+	return &CohereReranker{model: model, apiKey: os.Getenv("COHERE_API_KEY"), client: http.DefaultClient}
+}
+
+type cohereRerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+type cohereRerankResponse struct {
+	Results []struct {
+		Index          int     `json:"index"`
+		RelevanceScore float64 `json:"relevance_score"`
+	} `json:"results"`
+}
+
+func (c *CohereReranker) Rerank(ctx context.Context, query string, documents []string) ([]RerankResult, error) {
+	body, err := json.Marshal(cohereRerankRequest{Model: c.model, Query: query, Documents: documents})
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling cohere rerank request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cohereRerankURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building cohere rerank request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling cohere rerank: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("cohere rerank returned status %d: %s", resp.StatusCode, respBody)
+	}
+
+	var parsed cohereRerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("error decoding cohere rerank response: %w", err)
+	}
+
+	results := make([]RerankResult, len(parsed.Results))
+	for i, r := range parsed.Results {
+		results[i] = RerankResult{Index: r.Index, Score: r.RelevanceScore}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results, nil
+}
+
+// HTTPReranker calls a locally served cross-encoder (e.g. a BGE-reranker
+// model served over HTTP) that accepts a query plus a list of documents and
+// returns per-document scores.
+type HTTPReranker struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// NewHTTPReranker returns a Reranker that POSTs to a local cross-encoder
+// service at baseURL.
+func NewHTTPReranker(baseURL, model string) *HTTPReranker {
+	return &HTTPReranker{baseURL: baseURL, model: model, client: http.DefaultClient}
+}
+
+type httpRerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+}
+
+type httpRerankResponse struct {
+	Scores []float64 `json:"scores"`
+}
+
+func (h *HTTPReranker) Rerank(ctx context.Context, query string, documents []string) ([]RerankResult, error) {
+	body, err := json.Marshal(httpRerankRequest{Model: h.model, Query: query, Documents: documents})
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling rerank request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.baseURL+"/rerank", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error building rerank request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling local reranker: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("local reranker returned status %d: %s", resp.StatusCode, respBody)
+	}
+
+	var parsed httpRerankResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("error decoding rerank response: %w", err)
+	}
+
+	results := make([]RerankResult, len(parsed.Scores))
+	for i, score := range parsed.Scores {
+		results[i] = RerankResult{Index: i, Score: score}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results, nil
+}
@@ -1,25 +1,82 @@
 package models
 
+import "time"
+
 type SearchType string
 
 const (
 	SearchTypeSimilarity SearchType = "similarity"
 	SearchTypeMMR        SearchType = "mmr"
+	// SearchTypeHybrid combines pgvector similarity with Postgres full-text
+	// search over message content, fusing the two rankings with RRF.
+	SearchTypeHybrid SearchType = "hybrid"
+	// SearchTypeRerank recalls candidates by similarity, optionally
+	// diversifies them with MMR (when MMRLambda is set), then re-scores the
+	// survivors with a cross-encoder via llms.Reranker.
+	SearchTypeRerank SearchType = "rerank"
 )
 
+// SearchWeights controls how much each retrieval signal contributes to a
+// hybrid search. Weights are applied before RRF fusion; a zero value for
+// either field falls back to an equal 1.0 weight.
+type SearchWeights struct {
+	Vector  float32 `json:"vector,omitempty"`
+	Lexical float32 `json:"lexical,omitempty"`
+}
+
 type MemorySearchResult struct {
-	Message   *Message               `json:"message"`
-	Summary   *Summary               `json:"summary"` // reserved for future use
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
-	Dist      float64                `json:"dist"`
-	Embedding []float32              `json:"embedding"`
+	Message  *Message               `json:"message"`
+	Summary  *Summary               `json:"summary"` // reserved for future use
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Dist is a raw single-leg similarity score; it's meaningless once a
+	// result has been fused across legs (SearchTypeHybrid) and is omitted
+	// from JSON in that case. Score is the value to sort/display by instead.
+	Dist      float64   `json:"dist,omitempty"`
+	Score     float64   `json:"score,omitempty"`
+	Embedding []float32 `json:"embedding"`
 }
 
 type MemorySearchPayload struct {
-	Text      string                 `json:"text"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
-	Type      SearchType             `json:"type"`
-	MMRLambda float32                `json:"mmr_lambda,omitempty"`
+	Text     string                 `json:"text"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Type     SearchType             `json:"type"`
+	// Keywords, when set, drives the lexical leg of a hybrid search. If Text
+	// is also set, it is used for both the vector leg and, when Keywords is
+	// empty, the lexical leg.
+	Keywords  string         `json:"keywords,omitempty"`
+	Weights   *SearchWeights `json:"weights,omitempty"`
+	RRFK      int            `json:"rrf_k,omitempty"`
+	MMRLambda float32        `json:"mmr_lambda,omitempty"`
+	// RerankTopN bounds how many candidates survive cross-encoder reranking
+	// under SearchTypeRerank; 0 means keep all reranked candidates.
+	RerankTopN int `json:"rerank_top_n,omitempty"`
+	// RecencyWeight (0..1) blends a time-decay term into the final score:
+	// score = (1-RecencyWeight)*similarity + RecencyWeight*exp(-age/RecencyHalfLife).
+	// 0 (the default) leaves scoring untouched. RecencyHalfLife is the age at
+	// which the decay term drops to ~37%; it's required whenever
+	// RecencyWeight is set.
+	RecencyWeight   float32       `json:"recency_weight,omitempty"`
+	RecencyHalfLife time.Duration `json:"recency_half_life,omitempty"`
+	// Limit and Offset page through results; Cursor is an opaque alternative
+	// to Offset returned by a previous MemorySearchResultPage for callers
+	// that want to keep paging without tracking a raw offset themselves.
+	Limit  int     `json:"limit,omitempty"`
+	Offset int     `json:"offset,omitempty"`
+	Cursor *string `json:"cursor,omitempty"`
+}
+
+// MemorySearchResultPage mirrors DocumentSearchResultPage for memory search:
+// a page of results plus enough paging metadata for a caller to fetch the
+// next one.
+type MemorySearchResultPage struct {
+	Results     []MemorySearchResult `json:"results"`
+	QueryVector []float32            `json:"query_vector"`
+	ResultCount int                  `json:"result_count"`
+	TotalPages  int                  `json:"total_pages"`
+	CurrentPage int                  `json:"current_page"`
+	// Cursor pages to the next MemorySearchResultPage; nil once there are no
+	// more results.
+	Cursor *string `json:"cursor,omitempty"`
 }
 
 type DocumentSearchPayload struct {
@@ -0,0 +1,27 @@
+package search
+
+import "github.com/getzep/zep/pkg/models"
+
+// RerankMMR reranks results using the Maximal Marginal Relevance algorithm.
+// It's backend-agnostic: it only needs each result's embedding, so any
+// VectorStore's recall step can feed it, not just Postgres's.
+func RerankMMR(
+	results []models.MemorySearchResult,
+	queryEmbedding []float32,
+	lambda float32,
+	limit int,
+) ([]models.MemorySearchResult, error) {
+	embeddingList := make([][]float32, len(results))
+	for i, result := range results {
+		embeddingList[i] = result.Embedding
+	}
+	rerankedIdxs, err := MaximalMarginalRelevance(queryEmbedding, embeddingList, lambda, limit)
+	if err != nil {
+		return nil, err
+	}
+	rerankedResults := make([]models.MemorySearchResult, len(rerankedIdxs))
+	for i, idx := range rerankedIdxs {
+		rerankedResults[i] = results[idx]
+	}
+	return rerankedResults, nil
+}
@@ -0,0 +1,51 @@
+package search
+
+import (
+	"context"
+	"sort"
+
+	"github.com/getzep/zep/pkg/llms"
+	"github.com/getzep/zep/pkg/models"
+)
+
+// CrossEncoderRerank re-scores the given candidates against query with a
+// cross-encoder and returns the top topN, sorted by the new score. It's
+// meant to run as a final stage after recall (and, optionally, MMR
+// diversification): recall -> MMR diversify -> CrossEncoderRerank.
+func CrossEncoderRerank(
+	ctx context.Context,
+	reranker llms.Reranker,
+	query string,
+	candidates []models.MemorySearchResult,
+	topN int,
+) ([]models.MemorySearchResult, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	documents := make([]string, len(candidates))
+	for i, c := range candidates {
+		if c.Message != nil {
+			documents[i] = c.Message.Content
+		}
+	}
+
+	scored, err := reranker.Rerank(ctx, query, documents)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+
+	if topN <= 0 || topN > len(scored) {
+		topN = len(scored)
+	}
+
+	reranked := make([]models.MemorySearchResult, topN)
+	for i := 0; i < topN; i++ {
+		result := candidates[scored[i].Index]
+		result.Score = scored[i].Score
+		reranked[i] = result
+	}
+	return reranked, nil
+}
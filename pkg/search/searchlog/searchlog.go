@@ -0,0 +1,135 @@
+// Package searchlog adds structured logging and trace propagation around
+// memory/document search calls, following the same meta-logger pattern as
+// Milvus: one log line per (sampled) request carrying the fields needed to
+// answer "why did I get these results" without attaching a debugger.
+package searchlog
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+var tracer = otel.Tracer("github.com/getzep/zep/pkg/search")
+
+// Fields is everything we know about a single search request by the time it
+// completes. Durations are in milliseconds; zero means that stage didn't run,
+// took under a millisecond, or isn't timed on that path. The hybrid path of
+// searchMessages fills in only MMRLambda and ReturnedCount and leaves the
+// duration and CandidateCount fields at zero.
+type Fields struct {
+	SessionID      string
+	QueryLen       int
+	SearchType     string
+	EmbeddingMS    int64
+	DBMS           int64
+	RerankMS       int64
+	CandidateCount int
+	ReturnedCount  int
+	MMRLambda      float32
+}
+
+func (f Fields) zapFields() []zap.Field {
+	return []zap.Field{
+		zap.String("session_id", f.SessionID),
+		zap.Int("query_len", f.QueryLen),
+		zap.String("search_type", f.SearchType),
+		zap.Int64("embedding_ms", f.EmbeddingMS),
+		zap.Int64("db_ms", f.DBMS),
+		zap.Int64("rerank_ms", f.RerankMS),
+		zap.Int("candidate_count", f.CandidateCount),
+		zap.Int("returned_count", f.ReturnedCount),
+		zap.Float32("mmr_lambda", f.MMRLambda),
+	}
+}
+
+// Sampler decides whether a given call should be logged. Returning true on
+// every call is always a valid Sampler.
+type Sampler func() bool
+
+// NewRateSampler returns a Sampler that allows roughly 1 in every n calls
+// through, so high-QPS deployments can bound log volume. n <= 1 always
+// allows.
+func NewRateSampler(n int) Sampler {
+	if n <= 1 {
+		return func() bool { return true }
+	}
+	var count uint64
+	return func() bool {
+		return atomic.AddUint64(&count, 1)%uint64(n) == 0
+	}
+}
+
+// Logger emits one structured line per sampled search request and wraps the
+// call in an OpenTelemetry span for trace propagation.
+type Logger struct {
+	log    *zap.Logger
+	sample Sampler
+}
+
+// New returns a Logger that writes to log, sampled by sample. A nil sample
+// logs every call.
+func New(log *zap.Logger, sample Sampler) *Logger {
+	if sample == nil {
+		sample = NewRateSampler(1)
+	}
+	return &Logger{log: log, sample: sample}
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger = New(zap.NewNop(), nil)
+)
+
+// SetDefault installs l as the package-wide default Logger used by Default.
+func SetDefault(l *Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
+
+// Default returns the package-wide Logger installed by SetDefault, or a
+// no-op Logger if none has been installed.
+func Default() *Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+// StartSpan starts an OTel span named name and returns the derived context
+// plus a Finish func. Call Finish with the Fields gathered during the
+// search; it ends the span and, if this call was sampled, emits the
+// structured log line.
+func (l *Logger) StartSpan(
+	ctx context.Context,
+	name string,
+	sessionID string,
+	searchType string,
+	queryLen int,
+) (context.Context, func(Fields)) {
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(
+		attribute.String("session_id", sessionID),
+		attribute.String("search_type", searchType),
+	))
+	sampled := l.sample()
+	start := time.Now()
+
+	return ctx, func(f Fields) {
+		span.SetAttributes(attribute.Int64("duration_ms", time.Since(start).Milliseconds()))
+		span.End()
+
+		if !sampled {
+			return
+		}
+		f.SessionID = sessionID
+		f.SearchType = searchType
+		f.QueryLen = queryLen
+		l.log.Info(name, f.zapFields()...)
+	}
+}
@@ -0,0 +1,83 @@
+package milvus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/getzep/zep/pkg/store"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+// toMilvusExpr translates a backend-agnostic store.Filter into a Milvus
+// boolean expr string, ANDing together one `metadata["key"] == "value"` /
+// `metadata["key"] == value` clause per entry. Milvus has no native JSONPath support, so nested
+// metadata filters (store.JSONQuery-style and/or trees) aren't representable
+// here; only flat equality filters on top-level metadata keys are supported.
+// filter.SessionID, when set, is ANDed in as an equality clause on
+// fieldSessionID so MessageCollection search stays scoped to one chat
+// session instead of searching across every session in the collection.
+func toMilvusExpr(filter store.Filter) string {
+	clauses := make([]string, 0, len(filter.Where)+1)
+	if filter.SessionID != "" {
+		clauses = append(clauses, fmt.Sprintf("%s == \"%s\"", fieldSessionID, filter.SessionID))
+	}
+	for k, v := range filter.Where {
+		switch val := v.(type) {
+		case string:
+			clauses = append(clauses, fmt.Sprintf("%s[\"%s\"] == \"%s\"", fieldMetadata, k, val))
+		default:
+			clauses = append(clauses, fmt.Sprintf("%s[\"%s\"] == %v", fieldMetadata, k, val))
+		}
+	}
+	return strings.Join(clauses, " and ")
+}
+
+func quotedList(ids []string) string {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = fmt.Sprintf("%q", id)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func encodeMetadata(m map[string]interface{}) []byte {
+	if m == nil {
+		return []byte("{}")
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return []byte("{}")
+	}
+	return b
+}
+
+func decodeMetadata(b []byte) map[string]interface{} {
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+func fieldColumnString(fields []entity.Column, name string, idx int) string {
+	for _, f := range fields {
+		if f.Name() == name {
+			if col, ok := f.(*entity.ColumnVarChar); ok {
+				return col.Data()[idx]
+			}
+		}
+	}
+	return ""
+}
+
+func fieldColumnBytes(fields []entity.Column, name string, idx int) []byte {
+	for _, f := range fields {
+		if f.Name() == name {
+			if col, ok := f.(*entity.ColumnJSONBytes); ok {
+				return col.Data()[idx]
+			}
+		}
+	}
+	return nil
+}
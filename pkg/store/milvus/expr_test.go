@@ -0,0 +1,38 @@
+package milvus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getzep/zep/pkg/store"
+)
+
+func TestToMilvusExpr_EmptyFilter(t *testing.T) {
+	if expr := toMilvusExpr(store.Filter{}); expr != "" {
+		t.Errorf("expected empty expr for empty filter, got %q", expr)
+	}
+}
+
+func TestToMilvusExpr_ScopesToSession(t *testing.T) {
+	expr := toMilvusExpr(store.Filter{SessionID: "session-123"})
+	want := fieldSessionID + " == \"session-123\""
+	if expr != want {
+		t.Errorf("expected session scope clause %q, got %q", want, expr)
+	}
+}
+
+func TestToMilvusExpr_SessionAndMetadataAreANDed(t *testing.T) {
+	expr := toMilvusExpr(store.Filter{
+		SessionID: "session-123",
+		Where:     map[string]interface{}{"foo": "bar"},
+	})
+	if !strings.Contains(expr, fieldSessionID+" == \"session-123\"") {
+		t.Errorf("expected session scope clause in %q", expr)
+	}
+	if !strings.Contains(expr, "metadata[\"foo\"] == \"bar\"") {
+		t.Errorf("expected metadata clause in %q", expr)
+	}
+	if !strings.Contains(expr, " and ") {
+		t.Errorf("expected session and metadata clauses ANDed, got %q", expr)
+	}
+}
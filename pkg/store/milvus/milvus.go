@@ -0,0 +1,170 @@
+// Package milvus implements store.VectorStore against a Milvus cluster,
+// mirroring its collection/segment/search flow: collections are created with
+// an explicit schema, records are upserted as a column batch, and search
+// runs an ANN query scoped by an optional boolean expr filter.
+package milvus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getzep/zep/pkg/store"
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+)
+
+const (
+	fieldID        = "id"
+	fieldEmbedding = "embedding"
+	fieldContent   = "content"
+	fieldMetadata  = "metadata"
+	// fieldSessionID scopes MessageCollection records to the chat session
+	// that owns them; see store.Filter's SessionID doc comment. Records in
+	// other collections leave it empty.
+	fieldSessionID = "session_id"
+
+	defaultIndexType  = entity.HNSW
+	defaultMetricType = entity.IP
+)
+
+// Store is the Milvus-backed store.VectorStore implementation.
+type Store struct {
+	client client.Client
+	expr   string
+}
+
+// New returns a store.VectorStore backed by an existing Milvus client
+// connection.
+func New(c client.Client) *Store {
+	return &Store{client: c}
+}
+
+func (s *Store) CreateCollection(ctx context.Context, collection string, dimension int) error {
+	schema := &entity.Schema{
+		CollectionName: collection,
+		Fields: []*entity.Field{
+			{Name: fieldID, DataType: entity.FieldTypeVarChar, PrimaryKey: true, TypeParams: map[string]string{"max_length": "64"}},
+			{Name: fieldEmbedding, DataType: entity.FieldTypeFloatVector, TypeParams: map[string]string{"dim": fmt.Sprintf("%d", dimension)}},
+			{Name: fieldContent, DataType: entity.FieldTypeVarChar, TypeParams: map[string]string{"max_length": "65535"}},
+			{Name: fieldMetadata, DataType: entity.FieldTypeJSON},
+			{Name: fieldSessionID, DataType: entity.FieldTypeVarChar, TypeParams: map[string]string{"max_length": "64"}},
+		},
+	}
+	if err := s.client.CreateCollection(ctx, schema, 2); err != nil {
+		return store.NewStorageError("error creating milvus collection", err)
+	}
+	index, err := entity.NewIndexHNSW(defaultMetricType, 16, 64)
+	if err != nil {
+		return store.NewStorageError("error building milvus index params", err)
+	}
+	if err := s.client.CreateIndex(ctx, collection, fieldEmbedding, index, false); err != nil {
+		return store.NewStorageError("error creating milvus index", err)
+	}
+	return s.client.LoadCollection(ctx, collection, false)
+}
+
+func (s *Store) DropCollection(ctx context.Context, collection string) error {
+	if err := s.client.DropCollection(ctx, collection); err != nil {
+		return store.NewStorageError("error dropping milvus collection", err)
+	}
+	return nil
+}
+
+func (s *Store) Upsert(ctx context.Context, collection string, records []store.VectorRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(records))
+	contents := make([]string, len(records))
+	metadata := make([][]byte, len(records))
+	vectors := make([][]float32, len(records))
+	sessionIDs := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.ID
+		contents[i] = r.Content
+		vectors[i] = r.Embedding
+		metadata[i] = encodeMetadata(r.Metadata)
+		if r.Message != nil {
+			sessionIDs[i] = r.Message.SessionID
+		}
+	}
+
+	columns := []entity.Column{
+		entity.NewColumnVarChar(fieldID, ids),
+		entity.NewColumnVarChar(fieldContent, contents),
+		entity.NewColumnJSONBytes(fieldMetadata, metadata),
+		entity.NewColumnVarChar(fieldSessionID, sessionIDs),
+		entity.NewColumnFloatVector(fieldEmbedding, len(records[0].Embedding), vectors),
+	}
+
+	if _, err := s.client.Upsert(ctx, collection, "", columns...); err != nil {
+		return store.NewStorageError("error upserting milvus records", err)
+	}
+	return nil
+}
+
+func (s *Store) Search(
+	ctx context.Context,
+	collection string,
+	queryEmbedding []float32,
+	topK int,
+) ([]store.VectorMatch, error) {
+	sp, err := entity.NewIndexHNSWSearchParam(64)
+	if err != nil {
+		return nil, store.NewStorageError("error building milvus search params", err)
+	}
+
+	results, err := s.client.Search(
+		ctx,
+		collection,
+		nil,
+		s.expr,
+		[]string{fieldContent, fieldMetadata},
+		[]entity.Vector{entity.FloatVector(queryEmbedding)},
+		fieldEmbedding,
+		defaultMetricType,
+		topK,
+		sp,
+	)
+	if err != nil {
+		return nil, store.NewStorageError("error searching milvus", err)
+	}
+
+	var matches []store.VectorMatch
+	for _, r := range results {
+		for i := 0; i < r.ResultCount; i++ {
+			id, err := r.IDs.GetAsString(i)
+			if err != nil {
+				return nil, store.NewStorageError("error reading milvus result id", err)
+			}
+			matches = append(matches, store.VectorMatch{
+				Record: store.VectorRecord{
+					ID:       id,
+					Metadata: decodeMetadata(fieldColumnBytes(r.Fields, fieldMetadata, i)),
+					Content:  fieldColumnString(r.Fields, fieldContent, i),
+				},
+				Score: float64(r.Scores[i]),
+			})
+		}
+	}
+	return matches, nil
+}
+
+func (s *Store) Delete(ctx context.Context, collection string, ids []string) error {
+	expr := fmt.Sprintf("%s in %s", fieldID, quotedList(ids))
+	if err := s.client.Delete(ctx, collection, "", expr); err != nil {
+		return store.NewStorageError("error deleting milvus records", err)
+	}
+	return nil
+}
+
+// WithFilter returns a copy of s whose Search calls are scoped by filter,
+// translated to a Milvus boolean expr string. It does not mutate s.
+func (s *Store) WithFilter(filter store.Filter) store.VectorStore {
+	scoped := *s
+	scoped.expr = toMilvusExpr(filter)
+	return &scoped
+}
+
+var _ store.VectorStore = (*Store)(nil)
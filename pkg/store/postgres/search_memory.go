@@ -2,20 +2,32 @@ package postgres
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"math"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/getzep/zep/pkg/llms"
 	"github.com/getzep/zep/pkg/models"
 	"github.com/getzep/zep/pkg/search"
+	"github.com/getzep/zep/pkg/search/searchlog"
 	"github.com/getzep/zep/pkg/store"
 	"github.com/pgvector/pgvector-go"
 	"github.com/uptrace/bun"
+	"golang.org/x/sync/errgroup"
 )
 
 const DefaultMemorySearchLimit = 10
 
+// DefaultRRFK is the rank offset used by Reciprocal Rank Fusion when neither
+// the caller nor the query specifies one. Smaller values give more weight to
+// top-ranked results; 60 is the value used in the original RRF paper and is a
+// sane default across result set sizes.
+const DefaultRRFK = 60
+
 type JSONQuery struct {
 	JSONPath string       `json:"jsonpath"`
 	And      []*JSONQuery `json:"and,omitempty"`
@@ -34,15 +46,48 @@ func searchMessages(
 		return nil, store.NewStorageError("nil query or appState received", nil)
 	}
 
-	if query.Text == "" && len(query.Metadata) == 0 {
+	if query.Text == "" && query.Keywords == "" && len(query.Metadata) == 0 {
 		return nil, store.NewStorageError("empty query", errors.New("empty query"))
 	}
 
-	dbQuery := buildMessagesSelectQuery(ctx, db, query)
+	ctx, finishLog := searchlog.Default().StartSpan(ctx, "searchMessages", sessionID, string(query.Type), len(query.Text))
+	fields := searchlog.Fields{MMRLambda: query.MMRLambda}
+	var finalResults []models.MemorySearchResult
+	defer func() {
+		fields.ReturnedCount = len(finalResults)
+		finishLog(fields)
+	}()
+
+	if query.Type == models.SearchTypeHybrid {
+		results, err := searchMessagesHybrid(ctx, appState, db, sessionID, query, limit)
+		finalResults = results
+		return results, err
+	}
+
+	// The plain similarity path (no MMR/rerank/date-range filter) is simple
+	// enough to go through the backend-agnostic store.VectorStore returned by
+	// NewVectorStore. NewVectorStore only builds the Postgres backend from the
+	// `store.vector_backend` config key; any other value makes this path
+	// return an error.
+	if query.Type == models.SearchTypeSimilarity && query.Text != "" && len(query.Metadata) == 0 {
+		results, err := searchMessagesViaVectorStore(ctx, appState, db, sessionID, query, limit, &fields)
+		finalResults = results
+		return results, err
+	}
+
+	// Under SearchTypeRerank, MMR diversification is an optional pass before
+	// the cross-encoder stage: it only runs if the caller asked for it by
+	// setting MMRLambda. Computed up front so buildMessagesSelectQuery knows
+	// whether search.RerankMMR will need the embedding column.
+	useMMR := query.Type == models.SearchTypeMMR || (query.Type == models.SearchTypeRerank && query.MMRLambda != 0)
+
+	dbQuery := buildMessagesSelectQuery(ctx, db, query, useMMR)
 	var err error
 	var queryEmbedding []float32
 	if query.Text != "" {
+		embedStart := time.Now()
 		dbQuery, queryEmbedding, err = addMessagesVectorColumn(ctx, appState, dbQuery, query.Text)
+		fields.EmbeddingMS = time.Since(embedStart).Milliseconds()
 		if err != nil {
 			return nil, store.NewStorageError("error adding vector column", err)
 		}
@@ -67,9 +112,10 @@ func searchMessages(
 		limit = DefaultMemorySearchLimit
 	}
 
-	// If we're using MMR, we need to return more results than the limit so we can
-	// rerank them.
-	if query.Type == models.SearchTypeMMR {
+	// If we're using MMR (or cross-encoder reranking, which recalls a wider
+	// candidate pool to re-score), we need to return more results than the
+	// limit so we have something to rerank.
+	if useMMR || query.Type == models.SearchTypeRerank {
 		if query.MMRLambda == 0 {
 			query.MMRLambda = DefaultMMRLambda
 		}
@@ -78,45 +124,455 @@ func searchMessages(
 		dbQuery = dbQuery.Limit(limit)
 	}
 
+	dbStart := time.Now()
 	results, err := executeMessagesSearchScan(ctx, dbQuery)
+	fields.DBMS = time.Since(dbStart).Milliseconds()
 	if err != nil {
 		return nil, store.NewStorageError("memory searchMessages failed", err)
 	}
 
 	filteredResults := filterValidMessageSearchResults(results, query.Metadata)
+	fields.CandidateCount = len(filteredResults)
 
 	// If we're using MMR, rerank the results.
-	if query.Type == models.SearchTypeMMR {
-		filteredResults, err = rerankMMR(filteredResults, queryEmbedding, query.MMRLambda, limit)
+	if useMMR {
+		rerankStart := time.Now()
+		filteredResults, err = search.RerankMMR(filteredResults, queryEmbedding, query.MMRLambda, limit)
+		fields.RerankMS += time.Since(rerankStart).Milliseconds()
 		if err != nil {
 			return nil, store.NewStorageError("error applying mmr", err)
 		}
 	}
 
+	// Cross-encoder rerank: recall (and optional MMR diversify) above has
+	// already narrowed the field; re-score what's left and keep the top
+	// RerankTopN (or all of it, if unset).
+	if query.Type == models.SearchTypeRerank {
+		reranker, err := llms.GetReranker(appState)
+		if err != nil {
+			return nil, store.NewStorageError("error getting reranker", err)
+		}
+		rerankStart := time.Now()
+		filteredResults, err = search.CrossEncoderRerank(ctx, reranker, query.Text, filteredResults, query.RerankTopN)
+		fields.RerankMS += time.Since(rerankStart).Milliseconds()
+		if err != nil {
+			return nil, store.NewStorageError("error applying cross-encoder rerank", err)
+		}
+	}
+
+	filteredResults = applyRecencyWeighting(filteredResults, query.RecencyWeight, query.RecencyHalfLife, useMMR)
+
+	finalResults = filteredResults
 	return filteredResults, nil
 }
 
-// rerankMMR reranks the results using the Maximal Marginal Relevance algorithm
-func rerankMMR(results []models.MemorySearchResult, queryEmbedding []float32, lambda float32, limit int) ([]models.MemorySearchResult, error) {
-	embeddingList := make([][]float32, len(results))
-	for i, result := range results {
-		embeddingList[i] = result.Embedding
+// searchMessagesViaVectorStore runs the plain-similarity recall through
+// NewVectorStore instead of building a bun query directly, so it's
+// actually exercised for the default (Postgres) backend rather than sitting
+// next to the real search path unused.
+func searchMessagesViaVectorStore(
+	ctx context.Context,
+	appState *models.AppState,
+	db *bun.DB,
+	sessionID string,
+	query *models.MemorySearchPayload,
+	limit int,
+	fields *searchlog.Fields,
+) ([]models.MemorySearchResult, error) {
+	vectorStore, err := NewVectorStore(appState, db)
+	if err != nil {
+		return nil, store.NewStorageError("error constructing vector store", err)
+	}
+
+	embedStart := time.Now()
+	model, err := llms.GetEmbeddingModel(appState, "message")
+	if err != nil {
+		return nil, store.NewStorageError("failed to get message embedding model", err)
+	}
+	queryEmbeddings, err := llms.EmbedTexts(ctx, appState, model, "message", []string{query.Text})
+	if err != nil {
+		return nil, store.NewStorageError("failed to embed query", err)
+	}
+	fields.EmbeddingMS = time.Since(embedStart).Milliseconds()
+
+	if limit == 0 {
+		limit = DefaultMemorySearchLimit
+	}
+
+	dbStart := time.Now()
+	matches, err := vectorStore.WithFilter(store.Filter{SessionID: sessionID}).
+		Search(ctx, store.MessageCollection, queryEmbeddings[0], limit)
+	fields.DBMS = time.Since(dbStart).Milliseconds()
+	if err != nil {
+		return nil, store.NewStorageError("error searching vector store", err)
+	}
+
+	results := make([]models.MemorySearchResult, 0, len(matches))
+	for _, match := range matches {
+		if math.IsNaN(match.Score) {
+			continue
+		}
+		results = append(results, models.MemorySearchResult{
+			Message:  match.Record.Message,
+			Metadata: match.Record.Metadata,
+			Dist:     match.Score,
+		})
+	}
+	fields.CandidateCount = len(matches)
+
+	results = applyRecencyWeighting(results, query.RecencyWeight, query.RecencyHalfLife, false)
+	return results, nil
+}
+
+// searchMessagesHybrid runs the vector and lexical legs of a hybrid search
+// and fuses their rankings with Reciprocal Rank Fusion. When only keywords
+// are supplied, embedding is skipped entirely; when only text is supplied,
+// it's reused for the lexical leg.
+func searchMessagesHybrid(
+	ctx context.Context,
+	appState *models.AppState,
+	db *bun.DB,
+	sessionID string,
+	query *models.MemorySearchPayload,
+	limit int,
+) ([]models.MemorySearchResult, error) {
+	if limit == 0 {
+		limit = DefaultMemorySearchLimit
+	}
+
+	keywords := query.Keywords
+	if keywords == "" {
+		keywords = query.Text
+	}
+
+	rrfK := query.RRFK
+	if rrfK == 0 {
+		rrfK = DefaultRRFK
+	}
+
+	weights := query.Weights
+	if weights == nil {
+		weights = &models.SearchWeights{}
+	}
+	if weights.Vector == 0 {
+		weights.Vector = 1
+	}
+	if weights.Lexical == 0 {
+		weights.Lexical = 1
+	}
+
+	fusionLimit := limit * DefaultMMRMultiplier
+
+	var vectorResults, lexicalResults []models.MemorySearchResult
+	var err error
+
+	errG, errCtx := errgroup.WithContext(ctx)
+	if query.Text != "" {
+		errG.Go(func() error {
+			var gErr error
+			vectorResults, gErr = runMessagesVectorQuery(errCtx, appState, db, sessionID, query, fusionLimit)
+			return gErr
+		})
+	}
+	if keywords != "" {
+		errG.Go(func() error {
+			var gErr error
+			lexicalResults, gErr = runMessagesLexicalQuery(errCtx, db, sessionID, query, keywords, fusionLimit)
+			return gErr
+		})
+	}
+	if err = errG.Wait(); err != nil {
+		return nil, store.NewStorageError("hybrid search failed", err)
+	}
+
+	fused := fuseRRF(vectorResults, lexicalResults, weights, rrfK)
+	fused = applyRecencyWeighting(fused, query.RecencyWeight, query.RecencyHalfLife, false)
+	if len(fused) > limit {
+		fused = fused[:limit]
 	}
-	rerankedIdxs, err := search.MaximalMarginalRelevance(queryEmbedding, embeddingList, lambda, limit)
+	return fused, nil
+}
+
+// runMessagesVectorQuery runs the pgvector similarity leg of a hybrid search.
+func runMessagesVectorQuery(
+	ctx context.Context,
+	appState *models.AppState,
+	db *bun.DB,
+	sessionID string,
+	query *models.MemorySearchPayload,
+	limit int,
+) ([]models.MemorySearchResult, error) {
+	dbQuery := buildMessagesSelectQuery(ctx, db, query, false)
+	dbQuery, _, err := addMessagesVectorColumn(ctx, appState, dbQuery, query.Text)
 	if err != nil {
-		return nil, store.NewStorageError("error applying mmr", err)
+		return nil, store.NewStorageError("error adding vector column", err)
 	}
-	rerankedResults := make([]models.MemorySearchResult, len(rerankedIdxs))
-	for i, idx := range rerankedIdxs {
-		rerankedResults[i] = results[idx]
+	if len(query.Metadata) > 0 {
+		dbQuery, err = applyMessagesMetadataFilter(dbQuery, query.Metadata)
+		if err != nil {
+			return nil, store.NewStorageError("error applying metadata filter", err)
+		}
 	}
-	return rerankedResults, nil
+	dbQuery = dbQuery.Where("m.session_id = ?", sessionID).
+		Where("m.deleted_at IS NULL").
+		Order("dist DESC").
+		Limit(limit)
+
+	results, err := executeMessagesSearchScan(ctx, dbQuery)
+	if err != nil {
+		return nil, store.NewStorageError("memory searchMessages failed", err)
+	}
+	return filterValidMessageSearchResults(results, query.Metadata), nil
+}
+
+// runMessagesLexicalQuery runs the Postgres full-text search leg of a hybrid
+// search, ranking by ts_rank over the message's tsvector column.
+func runMessagesLexicalQuery(
+	ctx context.Context,
+	db *bun.DB,
+	sessionID string,
+	query *models.MemorySearchPayload,
+	keywords string,
+	limit int,
+) ([]models.MemorySearchResult, error) {
+	dbQuery := buildMessagesSelectQuery(ctx, db, query, false)
+	dbQuery = addMessagesFullTextColumn(dbQuery, keywords)
+
+	var err error
+	if len(query.Metadata) > 0 {
+		dbQuery, err = applyMessagesMetadataFilter(dbQuery, query.Metadata)
+		if err != nil {
+			return nil, store.NewStorageError("error applying metadata filter", err)
+		}
+	}
+	dbQuery = dbQuery.Where("m.session_id = ?", sessionID).
+		Where("m.deleted_at IS NULL").
+		Where("m.content_tsv @@ websearch_to_tsquery('english', ?)", keywords).
+		Order("dist DESC").
+		Limit(limit)
+
+	var results []models.MemorySearchResult
+	if err = dbQuery.Scan(ctx, &results); err != nil {
+		return nil, store.NewStorageError("memory lexical search failed", err)
+	}
+	return results, nil
+}
+
+// addMessagesFullTextColumn adds a column computing the ts_rank of the
+// message's tsvector against the given keywords, aliased to "dist" so it
+// composes with the existing sort and scan plumbing.
+func addMessagesFullTextColumn(q *bun.SelectQuery, keywords string) *bun.SelectQuery {
+	return q.ColumnExpr("ts_rank(m.content_tsv, websearch_to_tsquery('english', ?)) AS dist", keywords)
+}
+
+// fuseRRF merges two ranked result lists with Reciprocal Rank Fusion:
+// score = Σ weight_i / (k + rank_i), where rank_i is the 1-based position of
+// a message in result list i. Messages present in both lists accumulate both
+// terms; each result's Score field carries the fused value and results are
+// returned sorted by it, descending.
+func fuseRRF(
+	vector, lexical []models.MemorySearchResult,
+	weights *models.SearchWeights,
+	k int,
+) []models.MemorySearchResult {
+	scores := make(map[string]float64)
+	byUUID := make(map[string]models.MemorySearchResult)
+
+	accumulate := func(results []models.MemorySearchResult, weight float32) {
+		for rank, r := range results {
+			if r.Message == nil {
+				continue
+			}
+			id := r.Message.UUID.String()
+			scores[id] += float64(weight) / float64(k+rank+1)
+			byUUID[id] = r
+		}
+	}
+	accumulate(vector, weights.Vector)
+	accumulate(lexical, weights.Lexical)
+
+	fused := make([]models.MemorySearchResult, 0, len(byUUID))
+	for id, r := range byUUID {
+		r.Score = scores[id]
+		// Dist is a raw single-leg similarity score (cosine for the vector
+		// leg, ts_rank for the lexical leg); once a message is fused across
+		// both legs that value is no longer comparable to anything, so clear
+		// it rather than leave whichever leg happened to run last. Score
+		// (the RRF fusion of both legs) is the meaningful ranking signal here.
+		r.Dist = 0
+		fused = append(fused, r)
+	}
+	sort.Slice(fused, func(i, j int) bool { return fused[i].Score > fused[j].Score })
+
+	return fused
+}
+
+// searchMessagesPage runs a memory search and returns a MemorySearchResultPage
+// mirroring DocumentSearchResultPage, applying query.Limit/Offset (or the
+// equivalent opaque query.Cursor). For MMR searches, paging is applied after
+// reranking so "page 2" reflects the diversified order rather than the raw
+// candidate pool.
+func searchMessagesPage(
+	ctx context.Context,
+	appState *models.AppState,
+	db *bun.DB,
+	sessionID string,
+	query *models.MemorySearchPayload,
+) (*models.MemorySearchResultPage, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = DefaultMemorySearchLimit
+	}
+
+	offset := query.Offset
+	if query.Cursor != nil {
+		decoded, err := decodeMemorySearchCursor(*query.Cursor)
+		if err != nil {
+			return nil, store.NewStorageError("invalid cursor", err)
+		}
+		offset = decoded
+	}
+
+	// Fetch one row past the offset+limit window so paginateMemorySearchResults
+	// can tell whether there's a next page without a separate count query:
+	// the plain, MMR, hybrid and vector-store paths of searchMessages truncate
+	// their output to the limit they're given (a SearchTypeRerank search
+	// without MMR and with RerankTopN unset can return more), so fetching only
+	// offset+limit would make that window indistinguishable from "no more
+	// results" whenever there happen to be exactly that many matches.
+	fetchLimit := offset + limit + 1
+	results, err := searchMessages(ctx, appState, db, sessionID, query, fetchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// QueryVector would need the actual query embedding, not a result's
+	// message embedding; searchMessages doesn't return that embedding today,
+	// so rather than surface the wrong vector (a document embedding, not the
+	// query's), leave it nil until searchMessages is changed to return it.
+	page := paginateMemorySearchResults(results, offset, limit)
+	page.QueryVector = nil
+	return page, nil
+}
+
+// paginateMemorySearchResults slices a single page out of results, where
+// results is an offset+limit+1 window (one row past the page, fetched so we
+// can tell whether there's a next page) rather than the full candidate set.
+// Split out from searchMessagesPage so the offset/limit/cursor math can be
+// tested without a database.
+//
+// Because results only ever holds that one-extra-row peek rather than every
+// match, ResultCount and TotalPages are a lower bound, not the true total:
+// they count what's been seen so far and, once hasMore is true, the one
+// known-to-exist row past it.
+func paginateMemorySearchResults(
+	results []models.MemorySearchResult,
+	offset, limit int,
+) *models.MemorySearchResultPage {
+	hasMore := len(results) > offset+limit
+
+	end := offset + limit
+	if end > len(results) {
+		end = len(results)
+	}
+	var pageResults []models.MemorySearchResult
+	if offset < end {
+		pageResults = results[offset:end]
+	}
+
+	seen := end
+	if hasMore {
+		seen = end + 1
+	}
+
+	var nextCursor *string
+	if hasMore {
+		c := encodeMemorySearchCursor(offset + limit)
+		nextCursor = &c
+	}
+
+	totalPages := 1
+	if limit > 0 {
+		totalPages = (seen + limit - 1) / limit
+	}
+	currentPage := 1
+	if limit > 0 {
+		currentPage = offset/limit + 1
+	}
+
+	return &models.MemorySearchResultPage{
+		Results:     pageResults,
+		ResultCount: seen,
+		TotalPages:  totalPages,
+		CurrentPage: currentPage,
+		Cursor:      nextCursor,
+	}
+}
+
+// encodeMemorySearchCursor and decodeMemorySearchCursor keep the cursor
+// opaque to callers while it's really just a base64-encoded offset; this
+// leaves room to switch to a keyset cursor later without a payload change.
+func encodeMemorySearchCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeMemorySearchCursor(cursor string) (int, error) {
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(decoded))
+}
+
+// applyRecencyWeighting blends a time-decay term into each result's score:
+// score = (1-weight)*sim + weight*exp(-age/halfLife), where sim is the
+// result's fused Score if one was already set (hybrid/rerank) or its Dist
+// otherwise. When preserveOrder is true, the blended scores are recorded but
+// the slice is left in its existing order: MMR's diversification (and,
+// under SearchTypeRerank, the cross-encoder's scoring) has already picked
+// the order these results should come back in, and resorting by a recency-
+// blended score here would undo that in favor of plain relevance+recency.
+// Otherwise results are resorted by the blended score, descending.
+func applyRecencyWeighting(
+	results []models.MemorySearchResult,
+	weight float32,
+	halfLife time.Duration,
+	preserveOrder bool,
+) []models.MemorySearchResult {
+	if weight <= 0 || halfLife <= 0 {
+		return results
+	}
+
+	now := time.Now()
+	simWeight := float64(1 - weight)
+	for i := range results {
+		sim := results[i].Dist
+		if results[i].Score != 0 {
+			sim = results[i].Score
+		}
+
+		var recency float64
+		if results[i].Message != nil {
+			age := now.Sub(results[i].Message.CreatedAt)
+			recency = math.Exp(-age.Seconds() / halfLife.Seconds())
+		}
+
+		results[i].Score = simWeight*sim + float64(weight)*recency
+	}
+
+	if preserveOrder {
+		return results
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
 }
 
 func buildMessagesSelectQuery(
 	ctx context.Context,
 	db *bun.DB,
 	query *models.MemorySearchPayload,
+	needsEmbedding bool,
 ) *bun.SelectQuery {
 	dbQuery := db.NewSelect().TableExpr("message_embedding AS me").
 		Join("JOIN message AS m").
@@ -128,7 +584,10 @@ func buildMessagesSelectQuery(
 		ColumnExpr("m.metadata AS message__metadata").
 		ColumnExpr("m.token_count AS message__token_count")
 
-	if query.Type == models.SearchTypeMMR {
+	// The embedding column is needed by search.RerankMMR, which runs for
+	// SearchTypeMMR and, optionally, SearchTypeRerank when MMRLambda is set,
+	// and by PGVectorStore's message search, which returns it in each record.
+	if needsEmbedding {
 		dbQuery = dbQuery.ColumnExpr("me.embedding AS embedding")
 	}
 
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getzep/zep/pkg/models"
+	"github.com/google/uuid"
+)
+
+func newTestMessageResult(createdAt time.Time) models.MemorySearchResult {
+	return models.MemorySearchResult{
+		Message: &models.Message{
+			UUID:      uuid.New(),
+			CreatedAt: createdAt,
+		},
+	}
+}
+
+func TestFuseRRF(t *testing.T) {
+	now := time.Now()
+	a := newTestMessageResult(now)
+	b := newTestMessageResult(now)
+	c := newTestMessageResult(now)
+
+	// a ranks first in both legs, b only appears in the vector leg, c only in
+	// the lexical leg: a should come out on top, and b/c should still appear
+	// once each despite only being recalled by one leg.
+	vector := []models.MemorySearchResult{a, b}
+	lexical := []models.MemorySearchResult{a, c}
+	weights := &models.SearchWeights{Vector: 1, Lexical: 1}
+
+	fused := fuseRRF(vector, lexical, weights, DefaultRRFK)
+
+	if len(fused) != 3 {
+		t.Fatalf("expected 3 fused results, got %d", len(fused))
+	}
+	if fused[0].Message.UUID != a.Message.UUID {
+		t.Errorf("expected %s ranked first, got %s", a.Message.UUID, fused[0].Message.UUID)
+	}
+	for _, r := range fused {
+		if r.Score <= 0 {
+			t.Errorf("expected positive RRF score for %s, got %f", r.Message.UUID, r.Score)
+		}
+	}
+}
+
+func TestFuseRRF_ClearsDistForOverlappingResults(t *testing.T) {
+	now := time.Now()
+	a := newTestMessageResult(now)
+	a.Dist = 0.9 // vector leg's cosine similarity
+
+	aFromLexical := a
+	aFromLexical.Dist = 4.2 // lexical leg's ts_rank, a different scale entirely
+
+	weights := &models.SearchWeights{Vector: 1, Lexical: 1}
+	fused := fuseRRF([]models.MemorySearchResult{a}, []models.MemorySearchResult{aFromLexical}, weights, DefaultRRFK)
+
+	if len(fused) != 1 {
+		t.Fatalf("expected 1 fused result, got %d", len(fused))
+	}
+	if fused[0].Dist != 0 {
+		t.Errorf("expected Dist cleared on a result fused from both legs, got %f", fused[0].Dist)
+	}
+	if fused[0].Score == 0 {
+		t.Error("expected Score to still carry the fused RRF value")
+	}
+}
+
+func TestFuseRRF_WeightZeroesOutLeg(t *testing.T) {
+	now := time.Now()
+	a := newTestMessageResult(now)
+	b := newTestMessageResult(now)
+
+	vector := []models.MemorySearchResult{a}
+	lexical := []models.MemorySearchResult{b}
+	weights := &models.SearchWeights{Vector: 1, Lexical: 0}
+
+	fused := fuseRRF(vector, lexical, weights, DefaultRRFK)
+
+	for _, r := range fused {
+		if r.Message.UUID == b.Message.UUID && r.Score != 0 {
+			t.Errorf("expected zero-weight lexical leg to contribute no score, got %f", r.Score)
+		}
+	}
+}
+
+func TestApplyRecencyWeighting_NoOpWhenUnset(t *testing.T) {
+	results := []models.MemorySearchResult{newTestMessageResult(time.Now())}
+	out := applyRecencyWeighting(results, 0, time.Hour, false)
+	if out[0].Score != 0 {
+		t.Errorf("expected score untouched when weight is 0, got %f", out[0].Score)
+	}
+}
+
+func TestApplyRecencyWeighting_PrefersRecent(t *testing.T) {
+	now := time.Now()
+	old := newTestMessageResult(now.Add(-30 * 24 * time.Hour))
+	recent := newTestMessageResult(now)
+	old.Dist, recent.Dist = 0.5, 0.5
+
+	out := applyRecencyWeighting([]models.MemorySearchResult{old, recent}, 0.5, 24*time.Hour, false)
+
+	if out[0].Message.UUID != recent.Message.UUID {
+		t.Errorf("expected more recent result ranked first, got %s", out[0].Message.UUID)
+	}
+}
+
+func TestApplyRecencyWeighting_PreserveOrderSkipsResort(t *testing.T) {
+	now := time.Now()
+	old := newTestMessageResult(now.Add(-30 * 24 * time.Hour))
+	recent := newTestMessageResult(now)
+	old.Dist, recent.Dist = 0.5, 0.5
+
+	// old is first in the input; preserveOrder should keep it first even
+	// though recent would score higher, since MMR already chose this order.
+	out := applyRecencyWeighting([]models.MemorySearchResult{old, recent}, 0.5, 24*time.Hour, true)
+
+	if out[0].Message.UUID != old.Message.UUID {
+		t.Errorf("expected preserveOrder to keep original order, got %s first", out[0].Message.UUID)
+	}
+}
+
+func messageResults(n int) []models.MemorySearchResult {
+	now := time.Now()
+	results := make([]models.MemorySearchResult, n)
+	for i := range results {
+		results[i] = newTestMessageResult(now)
+	}
+	return results
+}
+
+func TestPaginateMemorySearchResults_ExactWindowStillHasMore(t *testing.T) {
+	// Regression test: searchMessages truncates its output to the fetchLimit
+	// it's given, so a full offset+limit page must not be mistaken for "no
+	// more results" when more exist - the caller over-fetches by one row
+	// (see searchMessagesPage) precisely so this case is distinguishable
+	// from a window that holds only offset+limit rows.
+	window := messageResults(3) // offset+limit+1 peek row included
+	page := paginateMemorySearchResults(window, 0, 2)
+
+	if len(page.Results) != 2 {
+		t.Fatalf("expected a 2-result page, got %d", len(page.Results))
+	}
+	if page.Cursor == nil {
+		t.Fatal("expected a cursor when the peek row confirms more results exist")
+	}
+}
+
+func TestPaginateMemorySearchResults_NoMoreWhenWindowIsShort(t *testing.T) {
+	window := messageResults(2) // no peek row: fewer than offset+limit+1 rows came back
+	page := paginateMemorySearchResults(window, 0, 2)
+
+	if len(page.Results) != 2 || page.ResultCount != 2 || page.TotalPages != 1 || page.CurrentPage != 1 {
+		t.Fatalf("unexpected final page: %+v", page)
+	}
+	if page.Cursor != nil {
+		t.Fatal("expected no cursor when the window is short of a peek row")
+	}
+}
+
+func TestPaginateMemorySearchResults_LastPage(t *testing.T) {
+	// 5 total matches, asking for offset=4/limit=2: only index 4 is left, and
+	// since there's nothing past it the window is short of a peek row too.
+	window := messageResults(5)
+	page := paginateMemorySearchResults(window, 4, 2)
+
+	if len(page.Results) != 1 || page.CurrentPage != 3 || page.Cursor != nil {
+		t.Fatalf("unexpected last page: %+v", page)
+	}
+}
+
+func TestPaginateMemorySearchResults_PastEnd(t *testing.T) {
+	pastEnd := paginateMemorySearchResults(nil, 10, 2)
+	if len(pastEnd.Results) != 0 || pastEnd.Cursor != nil {
+		t.Fatalf("unexpected past-end page: %+v", pastEnd)
+	}
+}
+
+func TestEncodeDecodeMemorySearchCursor(t *testing.T) {
+	cursor := encodeMemorySearchCursor(42)
+	offset, err := decodeMemorySearchCursor(cursor)
+	if err != nil {
+		t.Fatalf("unexpected error decoding cursor: %v", err)
+	}
+	if offset != 42 {
+		t.Errorf("expected offset 42, got %d", offset)
+	}
+
+	if _, err := decodeMemorySearchCursor("not-valid-base64!!"); err == nil {
+		t.Error("expected an error decoding an invalid cursor")
+	}
+}
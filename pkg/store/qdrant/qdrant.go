@@ -0,0 +1,148 @@
+// Package qdrant implements store.VectorStore against a Qdrant collection,
+// using Qdrant's native filter objects for metadata scoping rather than the
+// JSONPath (Postgres) or expr-string (Milvus) approaches used elsewhere.
+package qdrant
+
+import (
+	"context"
+
+	"github.com/getzep/zep/pkg/store"
+	"github.com/qdrant/go-client/qdrant"
+)
+
+// fieldSessionID is the payload key MessageCollection records are scoped by;
+// see store.Filter's SessionID doc comment. Records in other collections
+// leave it unset.
+const fieldSessionID = "session_id"
+
+// Store is the Qdrant-backed store.VectorStore implementation.
+type Store struct {
+	client *qdrant.Client
+	filter *qdrant.Filter
+}
+
+// New returns a store.VectorStore backed by an existing Qdrant client.
+func New(c *qdrant.Client) *Store {
+	return &Store{client: c}
+}
+
+func (s *Store) CreateCollection(ctx context.Context, collection string, dimension int) error {
+	return s.client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collection,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     uint64(dimension),
+			Distance: qdrant.Distance_Cosine,
+		}),
+	})
+}
+
+func (s *Store) DropCollection(ctx context.Context, collection string) error {
+	return s.client.DeleteCollection(ctx, collection)
+}
+
+func (s *Store) Upsert(ctx context.Context, collection string, records []store.VectorRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	points := make([]*qdrant.PointStruct, len(records))
+	for i, r := range records {
+		payload := make(map[string]*qdrant.Value, len(r.Metadata)+2)
+		payload["content"] = qdrant.NewValueString(r.Content)
+		if r.Message != nil {
+			payload[fieldSessionID] = qdrant.NewValueString(r.Message.SessionID)
+		}
+		for k, v := range r.Metadata {
+			payload[k] = qdrant.NewValue(v)
+		}
+		points[i] = &qdrant.PointStruct{
+			Id:      qdrant.NewIDUUID(r.ID),
+			Vectors: qdrant.NewVectors(r.Embedding...),
+			Payload: payload,
+		}
+	}
+	_, err := s.client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: collection,
+		Points:         points,
+	})
+	return err
+}
+
+func (s *Store) Search(
+	ctx context.Context,
+	collection string,
+	queryEmbedding []float32,
+	topK int,
+) ([]store.VectorMatch, error) {
+	limit := uint64(topK)
+	points, err := s.client.Query(ctx, &qdrant.QueryPoints{
+		CollectionName: collection,
+		Query:          qdrant.NewQuery(queryEmbedding...),
+		Filter:         s.filter,
+		Limit:          &limit,
+		WithPayload:    qdrant.NewWithPayload(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]store.VectorMatch, len(points))
+	for i, p := range points {
+		metadata := make(map[string]interface{}, len(p.Payload))
+		for k, v := range p.Payload {
+			if k == "content" || k == fieldSessionID {
+				continue
+			}
+			metadata[k] = v.AsInterface()
+		}
+		matches[i] = store.VectorMatch{
+			Record: store.VectorRecord{
+				ID:       p.Id.GetUuid(),
+				Content:  p.Payload["content"].GetStringValue(),
+				Metadata: metadata,
+			},
+			Score: float64(p.Score),
+		}
+	}
+	return matches, nil
+}
+
+func (s *Store) Delete(ctx context.Context, collection string, ids []string) error {
+	points := make([]*qdrant.PointId, len(ids))
+	for i, id := range ids {
+		points[i] = qdrant.NewIDUUID(id)
+	}
+	_, err := s.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: collection,
+		Points:         qdrant.NewPointsSelector(points...),
+	})
+	return err
+}
+
+// WithFilter returns a copy of s whose Search calls are scoped by filter,
+// translated to a Qdrant filter object. It does not mutate s.
+func (s *Store) WithFilter(filter store.Filter) store.VectorStore {
+	scoped := *s
+	scoped.filter = toQdrantFilter(filter)
+	return &scoped
+}
+
+// toQdrantFilter translates a backend-agnostic store.Filter into a Qdrant
+// filter object, ANDing together one equality match per metadata key.
+// filter.SessionID, when set, is ANDed in as a match on fieldSessionID so
+// MessageCollection search stays scoped to one chat session instead of
+// searching across every session in the collection.
+func toQdrantFilter(filter store.Filter) *qdrant.Filter {
+	if len(filter.Where) == 0 && filter.SessionID == "" {
+		return nil
+	}
+	conditions := make([]*qdrant.Condition, 0, len(filter.Where)+1)
+	if filter.SessionID != "" {
+		conditions = append(conditions, qdrant.NewMatch(fieldSessionID, filter.SessionID))
+	}
+	for k, v := range filter.Where {
+		conditions = append(conditions, qdrant.NewMatch(k, v))
+	}
+	return &qdrant.Filter{Must: conditions}
+}
+
+var _ store.VectorStore = (*Store)(nil)
@@ -0,0 +1,33 @@
+package qdrant
+
+import (
+	"testing"
+
+	"github.com/getzep/zep/pkg/store"
+)
+
+func TestToQdrantFilter_EmptyFilter(t *testing.T) {
+	if f := toQdrantFilter(store.Filter{}); f != nil {
+		t.Errorf("expected nil filter for empty filter, got %+v", f)
+	}
+}
+
+func TestToQdrantFilter_ScopesToSession(t *testing.T) {
+	f := toQdrantFilter(store.Filter{SessionID: "session-123"})
+	if f == nil {
+		t.Fatal("expected a non-nil filter when SessionID is set")
+	}
+	if len(f.Must) != 1 {
+		t.Fatalf("expected exactly one condition scoping to the session, got %d", len(f.Must))
+	}
+}
+
+func TestToQdrantFilter_SessionAndMetadataAreANDed(t *testing.T) {
+	f := toQdrantFilter(store.Filter{
+		SessionID: "session-123",
+		Where:     map[string]interface{}{"foo": "bar"},
+	})
+	if f == nil || len(f.Must) != 2 {
+		t.Fatalf("expected session scope and metadata clause ANDed, got %+v", f)
+	}
+}
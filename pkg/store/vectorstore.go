@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+
+	"github.com/getzep/zep/pkg/models"
+)
+
+// MessageCollection is the collection name VectorStore implementations use
+// for session chat history, as opposed to a named document collection.
+const MessageCollection = "message"
+
+// VectorBackend identifies which vector database implementation backs
+// message and document search. Selected via the `store.vector_backend`
+// config key; defaults to VectorBackendPostgres so existing deployments
+// keep using pgvector without any config changes.
+type VectorBackend string
+
+const (
+	VectorBackendPostgres VectorBackend = "postgres"
+	VectorBackendMilvus   VectorBackend = "milvus"
+	VectorBackendQdrant   VectorBackend = "qdrant"
+)
+
+// Filter is a backend-agnostic metadata predicate passed to WithFilter. Where
+// is translated per-backend (JSONPath for Postgres, an expr string for
+// Milvus, a filter object for Qdrant); SessionID additionally scopes the
+// MessageCollection to a single chat session.
+type Filter struct {
+	SessionID string
+	Where     map[string]interface{}
+}
+
+// VectorRecord is a single embedding plus the content and metadata needed to
+// upsert it into, or return it from, a VectorStore. Message is only used for
+// the MessageCollection, where it carries the full message row rather than
+// forcing callers to reassemble one from generic fields; other collections
+// leave it nil and use Content/Metadata instead. On Search, only
+// PGVectorStore fills it in; the Milvus and Qdrant stores read it on Upsert
+// (for the session ID) and return it nil.
+type VectorRecord struct {
+	ID        string
+	Embedding []float32
+	Content   string
+	Metadata  map[string]interface{}
+	Message   *models.Message
+}
+
+// VectorMatch is a single search hit returned by a VectorStore, with Score
+// in the backend's native similarity space (cosine, IP, etc).
+type VectorMatch struct {
+	Record VectorRecord
+	Score  float64
+}
+
+// VectorStore abstracts the message/document similarity search path so that
+// pgvector is one implementation among several rather than a hardcoded
+// dependency. MMR reranking (pkg/search) and RRF fusion (fuseRRF in
+// pkg/store/postgres) live above this interface; implementations only need
+// to handle storage, raw similarity
+// search, and metadata filtering in their own terms.
+type VectorStore interface {
+	// Upsert writes or overwrites records in the named collection.
+	Upsert(ctx context.Context, collection string, records []VectorRecord) error
+	// Search returns the topK nearest records to queryEmbedding in collection.
+	Search(ctx context.Context, collection string, queryEmbedding []float32, topK int) ([]VectorMatch, error)
+	// Delete removes records by ID from the named collection.
+	Delete(ctx context.Context, collection string, ids []string) error
+	// CreateCollection creates a collection sized for dimension-length embeddings.
+	CreateCollection(ctx context.Context, collection string, dimension int) error
+	// DropCollection removes a collection and all of its records.
+	DropCollection(ctx context.Context, collection string) error
+	// WithFilter returns a copy of this store scoped to filter, applied to
+	// every subsequent Search call. It does not mutate the receiver.
+	WithFilter(filter Filter) VectorStore
+}